@@ -0,0 +1,644 @@
+// Command csvclean is the CLI around the csvclean package. It owns the
+// concerns that only make sense at the command-line boundary -- flag
+// parsing, glob/multi-file expansion, stdin/stdout, atomic in-place
+// rename, and worker-pool dispatch -- and calls into Cleaner for the
+// actual record-by-record cleaning.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dcarbone/csvclean"
+)
+
+const (
+	helpText = `
+csvclean - simple character separated value escape utility
+
+Usage:
+	csvclean [options] infile [outfile]
+	csvclean [options] infile...
+	csvclean [options] glob...
+
+Options:
+	-b, --backup		Keep the pre-clean file as <infile>.bak (in-place only)
+	-c			Character comments are started with
+	-d			Character values are separated by
+	-e			Character to encapsulate values with
+	-h			Mark the input file as having a header
+	-i			Overwrite source file with updated contents
+	-j			Number of files to process concurrently (default: number of CPUs)
+	-m, --multi		Treat all arguments as independent inputs, even if exactly two are given
+	-n			Expected field count per record (default: inferred from the first record)
+	-p			Output file permission mask
+	-q			Quoting policy: minimal, all, non-numeric, or none
+	-t			Truncate output file prior to writing
+	-v			Enable verbose logging
+	--check, --lint		Validate input without rewriting it; reports malformed rows
+	--json			With --check, emit the report as JSON instead of plain text
+
+infile may be "-", or omitted entirely when data is available on stdin, to
+read from stdin. outfile may be "-", or omitted when reading from stdin, to
+write to stdout.
+
+If exactly two plain arguments are given, neither contains glob metacharacters,
+and none of -i, --check, or -m/--multi is set, they are treated as infile and
+outfile. Otherwise
+every argument is treated as an input file or glob pattern (shell-expanded
+patterns work as-is; quoted patterns like '*.csv' are expanded by csvclean
+itself) and processed independently, with up to -j of them running
+concurrently.
+
+A shell that expands an unquoted glob itself (e.g. "csvclean *.csv" with
+exactly two matching files) is indistinguishable on the wire from two
+literal filenames, so it is still read as infile/outfile; quote the
+pattern or pass -m/--multi to force independent-input handling.
+
+If -i is specified, outfile may not be specified, and infile may not be stdin
+If -i is NOT specified, outfile defaults to infile_clean.ext
+-i replaces infile atomically: the cleaned output is written to a temp file
+in the same directory, then renamed over infile once fully synced to disk
+-t and -p only function without -i
+
+--check validates each input and reports issues instead of writing cleaned
+output. There is no outfile in this mode: every argument is an input to
+validate. -t, -p, -b, and --backup are ignored, and -i only makes stdin be
+rejected as an input. The command exits non-zero if any file has issues or
+fails to parse.`
+
+	// stdinMarker is the conventional "read from stdin" / "write to
+	// stdout" argument.
+	stdinMarker = "-"
+)
+
+var (
+	logger = log.New(os.Stderr, "", log.LstdFlags)
+
+	fs              = flag.NewFlagSet("csvclean", flag.ContinueOnError)
+	backupPtr       = new(bool)
+	checkPtr        = new(bool)
+	commentPtr      = fs.String("c", "", "CSV comment start character")
+	rawDelimiterPtr = fs.String("d", ",", "CSV value delimiter")
+	encapsulatePtr  = fs.String("e", "\"", "Character to use for value encapsulation")
+	headerPtr       = fs.Bool("h", false, "Mark input file as having a header")
+	inPlacePtr      = fs.Bool("i", false, "Replace in place")
+	jobsPtr         = fs.Int("j", runtime.NumCPU(), "Number of files to process concurrently")
+	multiPtr        = new(bool)
+	fieldsPtr       = fs.Int("n", 0, "Expected field count per record (default: inferred from the first record)")
+	jsonPtr         = fs.Bool("json", false, "With --check, emit the report as JSON instead of plain text")
+	outPermPtr      = fs.Uint("p", 0666, "Permission mask to set to output file if it must be created")
+	quotingPtr      = fs.String("q", "minimal", "Quoting policy: minimal, all, non-numeric, or none")
+	truncatePtr     = fs.Bool("t", false, "Truncate output file prior to writing")
+	verbosePtr      = fs.Bool("v", false, "Enable verbose logging")
+)
+
+func init() {
+	fs.BoolVar(backupPtr, "b", false, "Keep the pre-clean file as <infile>.bak (in-place only)")
+	fs.BoolVar(backupPtr, "backup", false, "Alias for -b")
+	fs.BoolVar(checkPtr, "check", false, "Validate input without rewriting it")
+	fs.BoolVar(checkPtr, "lint", false, "Alias for -check")
+	fs.BoolVar(multiPtr, "m", false, "Treat all arguments as independent inputs, even if exactly two are given")
+	fs.BoolVar(multiPtr, "multi", false, "Alias for -m")
+}
+
+func logit(debug bool, f string, v ...interface{}) {
+	if debug && !*verbosePtr {
+		return
+	}
+	logger.Printf(f, v...)
+}
+
+func parseRune(in string) (rune, error) {
+	rs := []rune(in)
+	rsl := len(rs)
+	if rsl == 1 {
+		return rs[0], nil
+	}
+	if rsl == 2 && rs[1] == 't' {
+		return '\t', nil
+	}
+	return 0, fmt.Errorf("character must be a single byte character, saw %b", rs)
+}
+
+func parseQuotingPolicy(in string) (csvclean.QuotingPolicy, error) {
+	switch in {
+	case "minimal":
+		return csvclean.QuoteMinimal, nil
+	case "all":
+		return csvclean.QuoteAll, nil
+	case "non-numeric":
+		return csvclean.QuoteNonNumeric, nil
+	case "none":
+		return csvclean.QuoteNone, nil
+	default:
+		return 0, fmt.Errorf("unknown quoting policy %q, must be one of: minimal, all, non-numeric, none", in)
+	}
+}
+
+// stdinHasData reports whether os.Stdin is a pipe or redirected file, as
+// opposed to an interactive terminal with nothing queued up.
+func stdinHasData() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// looksLikeGlob reports whether arg contains any of the metacharacters
+// filepath.Match treats specially, i.e. whether it's plausibly a pattern
+// the user quoted for csvclean to expand itself, rather than a literal
+// filename.
+func looksLikeGlob(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// expandInput resolves a single CLI argument to one or more input paths.
+// stdinMarker is returned as-is. Glob patterns are expanded via
+// filepath.Glob; an argument that isn't a pattern, or matches nothing,
+// is returned unchanged so that the eventual open error names the path
+// the user actually typed.
+func expandInput(arg string) ([]string, error) {
+	if arg == stdinMarker {
+		return []string{arg}, nil
+	}
+	matches, err := filepath.Glob(arg)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing glob pattern %q: %w", arg, err)
+	}
+	if len(matches) == 0 {
+		return []string{arg}, nil
+	}
+	return matches, nil
+}
+
+// deriveOutputPath computes the default "infile_clean.ext" sibling path
+// for in. A basename with no extension (e.g. "README") becomes
+// "README_clean", with no trailing dot.
+func deriveOutputPath(in string) string {
+	base := filepath.Base(in)
+	bits := strings.SplitN(base, ".", 2)
+
+	var cleanBase string
+	if len(bits) == 2 {
+		cleanBase = fmt.Sprintf("%s_clean.%s", bits[0], bits[1])
+	} else {
+		cleanBase = fmt.Sprintf("%s_clean", bits[0])
+	}
+
+	return filepath.Join(filepath.Dir(in), cleanBase)
+}
+
+// job describes a single input/output pair to be run through the Cleaner.
+// An empty output means "clean in, in place".
+type job struct {
+	input  string
+	output string
+}
+
+// result is the outcome of running a job.
+type result struct {
+	job     job
+	stats   csvclean.Stats
+	elapsed time.Duration
+	err     error
+}
+
+// openInput opens in for reading, treating stdinMarker as os.Stdin.
+func openInput(in string) (*os.File, error) {
+	if in == stdinMarker {
+		logit(true, "Reading input from stdin")
+		return os.Stdin, nil
+	}
+
+	logit(true, "Opening input file %q...", in)
+
+	f, err := os.OpenFile(in, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error opening input file %q: %w", in, err)
+	}
+	return f, nil
+}
+
+// openJobFiles opens j's input and output, creating a same-directory temp
+// file when j.output is empty (in-place mode). It returns that temp file's
+// path so the caller can clean it up if anything goes wrong.
+func openJobFiles(j job) (inFile, outFile *os.File, tempPath string, err error) {
+	var outFlags = os.O_CREATE | os.O_WRONLY
+
+	if inFile, err = openInput(j.input); err != nil {
+		return nil, nil, "", err
+	}
+
+	if *truncatePtr && j.output != "" {
+		outFlags |= os.O_TRUNC
+	}
+
+	switch {
+	case j.output == stdinMarker:
+		logit(true, "Writing output to stdout")
+		outFile = os.Stdout
+	case j.output == "":
+		logit(true, "In-place overwrite specified, opening temp file next to %q...", j.input)
+		outFile, err = os.CreateTemp(filepath.Dir(j.input), fmt.Sprintf(".%s.csvclean.*", filepath.Base(j.input)))
+		if err != nil {
+			return inFile, nil, "", fmt.Errorf("error opening temporary file next to %q: %w", j.input, err)
+		}
+		tempPath = outFile.Name()
+	default:
+		logit(true, "Opening output file %q...", j.output)
+		if outFile, err = os.OpenFile(j.output, outFlags, os.FileMode(*outPermPtr)); err != nil {
+			return inFile, nil, "", fmt.Errorf("error opening output file %q: %w", j.output, err)
+		}
+	}
+	return inFile, outFile, tempPath, nil
+}
+
+// chownLike best-effort applies info's owner and group to path. It is not
+// an error if the calling process lacks permission to do so.
+func chownLike(path string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if err := os.Chown(path, int(stat.Uid), int(stat.Gid)); err != nil && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return nil
+}
+
+// finalizeInPlace publishes outFile over inputPath: it copies inFile's mode
+// and ownership onto outFile, fsyncs outFile, optionally preserves the
+// pre-clean contents as a backup file, atomically renames outFile into
+// place, and then fsyncs the parent directory.
+func finalizeInPlace(inFile, outFile *os.File, inputPath string) error {
+	info, err := inFile.Stat()
+	if err != nil {
+		return fmt.Errorf("error statting input file %q: %w", inputPath, err)
+	}
+
+	if err = outFile.Chmod(info.Mode()); err != nil {
+		return fmt.Errorf("error setting permissions on temp file %q: %w", outFile.Name(), err)
+	}
+	if err = chownLike(outFile.Name(), info); err != nil {
+		logit(false, "Warning: unable to preserve ownership of %q on temp file: %v", inputPath, err)
+	}
+	if err = outFile.Sync(); err != nil {
+		return fmt.Errorf("error syncing temp file %q: %w", outFile.Name(), err)
+	}
+
+	if *backupPtr {
+		backupPath := inputPath + ".bak"
+		logit(true, "Preserving pre-clean file as %q...", backupPath)
+		if err = os.Rename(inputPath, backupPath); err != nil {
+			return fmt.Errorf("error creating backup file %q: %w", backupPath, err)
+		}
+	}
+
+	if err = os.Rename(outFile.Name(), inputPath); err != nil {
+		return fmt.Errorf("error renaming temp file %q to %q: %w", outFile.Name(), inputPath, err)
+	}
+
+	dir, err := os.Open(filepath.Dir(inputPath))
+	if err != nil {
+		return fmt.Errorf("error opening directory of %q to sync: %w", inputPath, err)
+	}
+	defer func() { _ = dir.Close() }()
+	if err = dir.Sync(); err != nil {
+		return fmt.Errorf("error syncing directory of %q: %w", inputPath, err)
+	}
+
+	return nil
+}
+
+// removeTempFile removes path if non-empty, ignoring a not-exist error. It
+// is a no-op once finalizeInPlace has successfully renamed the temp file
+// into place.
+func removeTempFile(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logit(false, "Warning: unable to remove temp file %q: %v", path, err)
+	}
+}
+
+// isPlainJob reports whether j is a simple file-to-file job: neither
+// stdin/stdout nor in-place replacement, so it can be handed straight to
+// Cleaner.CleanFile.
+func isPlainJob(j job) bool {
+	return j.input != stdinMarker && j.output != "" && j.output != stdinMarker
+}
+
+// runJob processes a single job to completion.
+func runJob(ctx context.Context, cleaner *csvclean.Cleaner, j job) (csvclean.Stats, error) {
+	if isPlainJob(j) {
+		logit(true, "Opening input file %q...", j.input)
+		logit(true, "Opening output file %q...", j.output)
+		return cleaner.CleanFile(ctx, j.input, j.output, *truncatePtr, os.FileMode(*outPermPtr))
+	}
+
+	inFile, outFile, tempPath, err := openJobFiles(j)
+	if err != nil {
+		return csvclean.Stats{}, err
+	}
+
+	defer func() {
+		if inFile != nil && inFile != os.Stdin {
+			_ = inFile.Close()
+		}
+		if outFile != nil && outFile != os.Stdout {
+			_ = outFile.Close()
+		}
+	}()
+
+	stats, err := cleaner.Clean(ctx, inFile, outFile)
+	if err != nil {
+		removeTempFile(tempPath)
+		return stats, err
+	}
+
+	if ctx.Err() != nil {
+		removeTempFile(tempPath)
+		return stats, ctx.Err()
+	}
+
+	if j.output == "" {
+		if err = finalizeInPlace(inFile, outFile, j.input); err != nil {
+			removeTempFile(tempPath)
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// lintResult is the outcome of running a job through Cleaner.Lint.
+type lintResult struct {
+	job    job
+	report csvclean.Report
+	err    error
+}
+
+// runLintJob validates a single job's input and returns the resulting
+// Report without writing anything back out.
+func runLintJob(ctx context.Context, cleaner *csvclean.Cleaner, j job) (csvclean.Report, error) {
+	inFile, err := openInput(j.input)
+	if err != nil {
+		return csvclean.Report{}, err
+	}
+	defer func() {
+		if inFile != os.Stdin {
+			_ = inFile.Close()
+		}
+	}()
+
+	return cleaner.Lint(ctx, inFile)
+}
+
+func buildJobs(args []string) ([]job, error) {
+	if len(args) == 0 {
+		if !stdinHasData() {
+			return nil, errors.New("no input file provided and no data available on stdin")
+		}
+		if *inPlacePtr {
+			return nil, errors.New("-i cannot be used when reading from stdin")
+		}
+		return []job{{input: stdinMarker, output: stdinMarker}}, nil
+	}
+
+	// Exactly two plain arguments is the traditional "infile outfile" form,
+	// unless -i, --check, or -m/--multi is set, or either argument looks
+	// like a glob pattern csvclean itself would expand, in which case
+	// outfile wouldn't make sense (or the "two" is coincidental) and both
+	// arguments are instead treated as independent inputs.
+	if len(args) == 2 && !*inPlacePtr && !*checkPtr && !*multiPtr &&
+		!looksLikeGlob(args[0]) && !looksLikeGlob(args[1]) {
+		return []job{{input: args[0], output: args[1]}}, nil
+	}
+
+	var inputs []string
+	for _, arg := range args {
+		matches, err := expandInput(arg)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, matches...)
+	}
+
+	jobs := make([]job, 0, len(inputs))
+	for _, in := range inputs {
+		if in == stdinMarker {
+			if *inPlacePtr {
+				return nil, errors.New("-i cannot be used when reading from stdin")
+			}
+			jobs = append(jobs, job{input: in, output: stdinMarker})
+			continue
+		}
+		output := ""
+		if !*inPlacePtr {
+			output = deriveOutputPath(in)
+		}
+		jobs = append(jobs, job{input: in, output: output})
+	}
+	return jobs, nil
+}
+
+func main() {
+	var (
+		comma   rune
+		quote   rune
+		comment rune
+		err     error
+	)
+
+	if err = fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+	}
+
+	jobs, err := buildJobs(fs.Args())
+	if err != nil {
+		fmt.Println(err.Error())
+		fmt.Println(helpText)
+		os.Exit(1)
+	}
+
+	if comma, err = parseRune(*rawDelimiterPtr); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if len(*commentPtr) > 0 {
+		if comment, err = parseRune(*commentPtr); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+	if quote, err = parseRune(*encapsulatePtr); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	quotingPolicy, err := parseQuotingPolicy(*quotingPtr)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	logit(true, "Using %b (%q) as delimiter", comma, comma)
+
+	cleaner := csvclean.New(
+		csvclean.WithComma(comma),
+		csvclean.WithComment(comment),
+		csvclean.WithQuote(quote),
+		csvclean.WithHeader(*headerPtr),
+		csvclean.WithQuotingPolicy(quotingPolicy),
+		csvclean.WithFieldsPerRecord(*fieldsPtr),
+	)
+	if *verbosePtr {
+		cleaner.Logger = logger
+	}
+
+	workers := *jobsPtr
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	go func() {
+		sig := <-sigChan
+		logit(false, "Processing interrupted (%s)", sig)
+		cancel()
+	}()
+
+	if *checkPtr {
+		runCheck(ctx, cleaner, jobs, workers)
+		return
+	}
+
+	start := time.Now()
+
+	results := make([]result, len(jobs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			jobStart := time.Now()
+			stats, jobErr := runJob(ctx, cleaner, j)
+			results[i] = result{job: j, stats: stats, elapsed: time.Since(jobStart), err: jobErr}
+		}(i, j)
+	}
+
+	wg.Wait()
+
+	var errs []error
+	for _, r := range results {
+		fmt.Fprintf(os.Stderr, "%s: %d line(s) processed in %s\n", r.job.input, r.stats.LinesProcessed, r.elapsed)
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.job.input, r.err))
+		}
+	}
+
+	logit(true, "Processed %d file(s) in %s", len(jobs), time.Since(start))
+
+	if joined := errors.Join(errs...); joined != nil {
+		logit(false, "Errors occurred during execution: %v", joined)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// checkOutput is the JSON shape reported for a single file under --check
+// --json.
+type checkOutput struct {
+	File   string           `json:"file"`
+	Issues []csvclean.Issue `json:"issues"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// runCheck validates every job's input against cleaner and reports the
+// results, without writing anything back out. It exits non-zero if any
+// file has issues or failed to parse.
+func runCheck(ctx context.Context, cleaner *csvclean.Cleaner, jobs []job, workers int) {
+	results := make([]lintResult, len(jobs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			report, err := runLintJob(ctx, cleaner, j)
+			results[i] = lintResult{job: j, report: report, err: err}
+		}(i, j)
+	}
+
+	wg.Wait()
+
+	var dirty bool
+
+	if *jsonPtr {
+		out := make([]checkOutput, len(results))
+		for i, r := range results {
+			co := checkOutput{File: r.job.input, Issues: r.report.Issues}
+			if r.err != nil {
+				co.Error = r.err.Error()
+			}
+			out[i] = co
+			if r.err != nil || !r.report.OK() {
+				dirty = true
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	} else {
+		for _, r := range results {
+			if r.err != nil {
+				fmt.Printf("%s: error: %v\n", r.job.input, r.err)
+				dirty = true
+				continue
+			}
+			if r.report.OK() {
+				fmt.Printf("%s: OK\n", r.job.input)
+				continue
+			}
+			dirty = true
+			for _, issue := range r.report.Issues {
+				fmt.Printf("%s: %s\n", r.job.input, issue.String())
+			}
+		}
+	}
+
+	if dirty {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLooksLikeGlob(t *testing.T) {
+	cases := map[string]bool{
+		"a.csv":    false,
+		"*.csv":    true,
+		"a?.csv":   true,
+		"[ab].csv": true,
+	}
+	for in, want := range cases {
+		if got := looksLikeGlob(in); got != want {
+			t.Errorf("looksLikeGlob(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestDeriveOutputPath(t *testing.T) {
+	cases := map[string]string{
+		"a.csv":          "a_clean.csv",
+		"dir/a.csv":      "dir/a_clean.csv",
+		"/tmp/dir/a.csv": "/tmp/dir/a_clean.csv",
+		"README":         "README_clean",
+		"dir/README":     "dir/README_clean",
+	}
+	for in, want := range cases {
+		if got := deriveOutputPath(in); got != want {
+			t.Errorf("deriveOutputPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// withFlags temporarily sets the given bool flag pointers, restoring their
+// prior values on cleanup. It's how buildJobs's tests exercise -i/--check/-m
+// without going through fs.Parse.
+func withFlags(t *testing.T, inPlace, check, multi bool) {
+	t.Helper()
+	prevIn, prevCheck, prevMulti := *inPlacePtr, *checkPtr, *multiPtr
+	*inPlacePtr, *checkPtr, *multiPtr = inPlace, check, multi
+	t.Cleanup(func() {
+		*inPlacePtr, *checkPtr, *multiPtr = prevIn, prevCheck, prevMulti
+	})
+}
+
+func TestBuildJobs_TwoPlainArgsIsInfileOutfile(t *testing.T) {
+	withFlags(t, false, false, false)
+
+	jobs, err := buildJobs([]string{"a.csv", "b.csv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []job{{input: "a.csv", output: "b.csv"}}
+	if len(jobs) != 1 || jobs[0] != want[0] {
+		t.Fatalf("got %+v, want %+v", jobs, want)
+	}
+}
+
+func TestBuildJobs_GlobLikeArgsAreNotTreatedAsPair(t *testing.T) {
+	// Regression test: two arguments, one of which is itself a glob
+	// pattern, must not be silently collapsed into "infile outfile", or the
+	// second file's contents are destroyed.
+	withFlags(t, false, false, false)
+
+	jobs, err := buildJobs([]string{"*.csv", "b.csv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, j := range jobs {
+		if j.output == "b.csv" {
+			t.Fatalf("b.csv was treated as an output file: %+v", jobs)
+		}
+	}
+}
+
+func TestBuildJobs_ExtensionlessInputDoesNotPanic(t *testing.T) {
+	// Regression test: an extensionless filename in a multi-input batch
+	// used to panic inside deriveOutputPath, taking down every other job
+	// in the batch with it.
+	withFlags(t, false, false, true)
+
+	jobs, err := buildJobs([]string{"file1", "file2.csv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 independent jobs, got %+v", jobs)
+	}
+}
+
+func TestBuildJobs_MultiFlagForcesIndependentInputs(t *testing.T) {
+	withFlags(t, false, false, true)
+
+	jobs, err := buildJobs([]string{"a.csv", "b.csv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 independent jobs, got %+v", jobs)
+	}
+	for _, j := range jobs {
+		if j.output == "b.csv" || j.output == "a.csv" {
+			t.Fatalf("one input was treated as the other's output: %+v", jobs)
+		}
+	}
+}
+
+func TestBuildJobs_CheckModeTreatsTwoArgsIndependently(t *testing.T) {
+	withFlags(t, false, true, false)
+
+	jobs, err := buildJobs([]string{"a.csv", "b.csv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 independent jobs under --check, got %+v", jobs)
+	}
+}
+
+func TestOpenJobFiles_TruncatesExistingOutputFromStdin(t *testing.T) {
+	// Regression test: -t must truncate the output file even when the
+	// input is stdin; it previously left stale trailing bytes behind.
+	withFlags(t, false, false, false)
+
+	prevTruncate := *truncatePtr
+	*truncatePtr = true
+	t.Cleanup(func() { *truncatePtr = prevTruncate })
+
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(outPath, []byte("stale content that is much longer than the new content\n"), 0644); err != nil {
+		t.Fatalf("seeding output file: %v", err)
+	}
+
+	inFile, outFile, tempPath, err := openJobFiles(job{input: stdinMarker, output: outPath})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tempPath != "" {
+		t.Fatalf("did not expect a temp file for a non-in-place job")
+	}
+	_ = inFile // stdin; nothing to close in this test
+	if _, err := outFile.WriteString("a,b\n"); err != nil {
+		t.Fatalf("writing output: %v", err)
+	}
+	_ = outFile.Close()
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	if want := "a,b\n"; string(got) != want {
+		t.Fatalf("output file was not truncated: got %q, want %q", got, want)
+	}
+}
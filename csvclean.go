@@ -0,0 +1,210 @@
+// Package csvclean implements the cell-encapsulation logic behind the
+// csvclean command line tool, so it can be embedded in other Go programs.
+package csvclean
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// QuotingPolicy controls how output fields are quoted by a Cleaner. See
+// QuoteMinimal, QuoteAll, QuoteNonNumeric, and QuoteNone.
+type QuotingPolicy int
+
+// Stats describes the outcome of a single Clean or CleanFile call.
+type Stats struct {
+	// LinesProcessed is the number of records read from the input.
+	LinesProcessed uint64
+}
+
+// Cleaner re-encapsulates the fields of a delimited text stream. The zero
+// value is not usable; construct one with New.
+type Cleaner struct {
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// Comment, if non-zero, marks the start of a comment line in the
+	// input, per encoding/csv.Reader.
+	Comment rune
+	// Quote is the character used to encapsulate output fields.
+	Quote rune
+	// Header indicates the input's first line is a header. It is exempt
+	// from QuotingPolicy and written as read, except that when output goes
+	// through encoding/csv.Writer (see Clean) the writer still quotes any
+	// header field that needs it.
+	Header bool
+	// QuotingPolicy determines which fields get quoted on output.
+	QuotingPolicy QuotingPolicy
+	// LazyQuotes is passed through to the underlying csv.Reader.
+	LazyQuotes bool
+	// FieldsPerRecord is passed through to the underlying csv.Reader. A
+	// value of 0 means "use the field count of the first record", -1
+	// disables the check entirely.
+	FieldsPerRecord int
+	// TrimLeadingSpace is passed through to the underlying csv.Reader.
+	TrimLeadingSpace bool
+	// Logger, if non-nil, receives verbose per-line diagnostics.
+	Logger *log.Logger
+}
+
+// Option mutates a Cleaner at construction time.
+type Option func(*Cleaner)
+
+// WithComma sets the field delimiter.
+func WithComma(r rune) Option {
+	return func(c *Cleaner) { c.Comma = r }
+}
+
+// WithComment sets the comment marker.
+func WithComment(r rune) Option {
+	return func(c *Cleaner) { c.Comment = r }
+}
+
+// WithQuote sets the character used to encapsulate output fields.
+func WithQuote(r rune) Option {
+	return func(c *Cleaner) { c.Quote = r }
+}
+
+// WithHeader marks the input as having a header line.
+func WithHeader(header bool) Option {
+	return func(c *Cleaner) { c.Header = header }
+}
+
+// WithQuotingPolicy sets the output quoting policy.
+func WithQuotingPolicy(p QuotingPolicy) Option {
+	return func(c *Cleaner) { c.QuotingPolicy = p }
+}
+
+// WithLazyQuotes enables or disables lazy quote parsing on input.
+func WithLazyQuotes(lazy bool) Option {
+	return func(c *Cleaner) { c.LazyQuotes = lazy }
+}
+
+// WithFieldsPerRecord sets the expected field count for input records.
+func WithFieldsPerRecord(n int) Option {
+	return func(c *Cleaner) { c.FieldsPerRecord = n }
+}
+
+// WithTrimLeadingSpace enables or disables leading whitespace trimming on
+// input fields.
+func WithTrimLeadingSpace(trim bool) Option {
+	return func(c *Cleaner) { c.TrimLeadingSpace = trim }
+}
+
+// WithLogger sets the logger used for verbose diagnostics.
+func WithLogger(l *log.Logger) Option {
+	return func(c *Cleaner) { c.Logger = l }
+}
+
+// New constructs a Cleaner with csvclean's historical defaults applied,
+// then applies opts on top.
+func New(opts ...Option) *Cleaner {
+	c := &Cleaner{
+		Comma: ',',
+		Quote: '"',
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Cleaner) logf(f string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(f, v...)
+	}
+}
+
+// Clean reads delimited records from r, re-encapsulates their fields
+// according to c's configuration, and writes the result to w. It returns
+// as soon as ctx is cancelled, r is exhausted, or an error occurs.
+//
+// Under QuoteMinimal with '"' as Quote, the decision of which fields to
+// quote, and their escaping, is delegated entirely to encoding/csv.Writer.
+// Every other combination decides quoting itself and writes pre-escaped
+// fields, since encoding/csv.Writer can neither be told to quote a field
+// it wouldn't otherwise quote nor use a quote character other than '"';
+// those write through a plain bufio.Writer instead, so csv.Writer is only
+// ever constructed for the one case that actually uses it.
+func (c *Cleaner) Clean(ctx context.Context, r io.Reader, w io.Writer) (Stats, error) {
+	var stats Stats
+
+	reader := csv.NewReader(r)
+	reader.Comma = c.Comma
+	reader.Comment = c.Comment
+	reader.LazyQuotes = c.LazyQuotes
+	reader.FieldsPerRecord = c.FieldsPerRecord
+	reader.TrimLeadingSpace = c.TrimLeadingSpace
+
+	var csvWriter *csv.Writer
+	var rawWriter *bufio.Writer
+	if c.QuotingPolicy == QuoteMinimal && c.Quote == '"' {
+		csvWriter = csv.NewWriter(w)
+		csvWriter.Comma = c.Comma
+	} else {
+		rawWriter = bufio.NewWriter(w)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, nil
+		}
+
+		inputLine, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return stats, fmt.Errorf("error reading input record %d: %w", stats.LinesProcessed+1, err)
+		}
+
+		stats.LinesProcessed++
+
+		c.logf("Processing input: %v", inputLine)
+
+		isHeader := stats.LinesProcessed == 1 && c.Header
+
+		var updatedLine []string
+		if isHeader || csvWriter != nil {
+			updatedLine = inputLine
+		} else {
+			updatedLine = make([]string, len(inputLine))
+			for i, value := range inputLine {
+				quote, err := c.shouldQuote(value)
+				if err != nil {
+					return stats, fmt.Errorf("error processing record %d: %w", stats.LinesProcessed, err)
+				}
+				if quote {
+					updatedLine[i] = c.quoteField(value)
+				} else {
+					updatedLine[i] = value
+				}
+			}
+		}
+
+		c.logf("Updated line: %v", updatedLine)
+
+		if csvWriter != nil {
+			if err = csvWriter.Write(updatedLine); err != nil {
+				return stats, fmt.Errorf("error writing record %d: %w", stats.LinesProcessed, err)
+			}
+		} else if _, err = fmt.Fprintln(rawWriter, strings.Join(updatedLine, string(c.Comma))); err != nil {
+			return stats, fmt.Errorf("error writing record %d: %w", stats.LinesProcessed, err)
+		}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return stats, fmt.Errorf("error flushing output: %w", err)
+		}
+	} else if err := rawWriter.Flush(); err != nil {
+		return stats, fmt.Errorf("error flushing output: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,45 @@
+package csvclean
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestCleaner_Clean(t *testing.T) {
+	c := New(WithComma(','), WithQuote('"'), WithHeader(true), WithQuotingPolicy(QuoteAll))
+
+	in := bytes.NewBufferString("name,age\nalice,30\nbob,40\n")
+	var out bytes.Buffer
+
+	stats, err := c.Clean(context.Background(), in, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.LinesProcessed != 3 {
+		t.Fatalf("expected 3 lines processed, got %d", stats.LinesProcessed)
+	}
+
+	want := "name,age\n\"alice\",\"30\"\n\"bob\",\"40\"\n"
+	if got := out.String(); got != want {
+		t.Fatalf("unexpected output:\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestCleaner_Clean_ContextCancelled(t *testing.T) {
+	c := New(WithComma(','))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	in := bytes.NewBufferString("a,b\nc,d\n")
+	var out bytes.Buffer
+
+	stats, err := c.Clean(ctx, in, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.LinesProcessed != 0 {
+		t.Fatalf("expected 0 lines processed after cancellation, got %d", stats.LinesProcessed)
+	}
+}
@@ -0,0 +1,42 @@
+package csvclean
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// CleanFile opens in for reading and out for writing, applies opts on top
+// of c's existing configuration, and runs Clean between them. out is
+// created with perm if it does not already exist. truncate controls
+// whether out's existing contents are discarded before writing; with
+// truncate false, writing output shorter than out's current contents
+// leaves stale trailing bytes behind, matching the CLI's "-t" semantics.
+//
+// CleanFile is for the plain file-to-file case. It does not handle
+// stdin/stdout (there's no path to open) or atomic in-place replacement
+// (the caller must stage that itself, as cmd/csvclean does).
+func (c *Cleaner) CleanFile(ctx context.Context, in, out string, truncate bool, perm os.FileMode, opts ...Option) (Stats, error) {
+	cc := *c
+	for _, opt := range opts {
+		opt(&cc)
+	}
+
+	inFile, err := os.OpenFile(in, os.O_RDONLY, 0)
+	if err != nil {
+		return Stats{}, fmt.Errorf("error opening input file %q: %w", in, err)
+	}
+	defer func() { _ = inFile.Close() }()
+
+	outFlags := os.O_CREATE | os.O_WRONLY
+	if truncate {
+		outFlags |= os.O_TRUNC
+	}
+	outFile, err := os.OpenFile(out, outFlags, perm)
+	if err != nil {
+		return Stats{}, fmt.Errorf("error opening output file %q: %w", out, err)
+	}
+	defer func() { _ = outFile.Close() }()
+
+	return cc.Clean(ctx, inFile, outFile)
+}
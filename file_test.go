@@ -0,0 +1,67 @@
+package csvclean
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleaner_CleanFile(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.csv")
+	out := filepath.Join(dir, "out.csv")
+
+	if err := os.WriteFile(in, []byte("alice,30\n"), 0644); err != nil {
+		t.Fatalf("seeding input file: %v", err)
+	}
+
+	c := New(WithComma(','), WithQuote('"'), WithQuotingPolicy(QuoteAll))
+
+	stats, err := c.CleanFile(context.Background(), in, out, true, 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.LinesProcessed != 1 {
+		t.Fatalf("expected 1 line processed, got %d", stats.LinesProcessed)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	if want := "\"alice\",\"30\"\n"; string(got) != want {
+		t.Fatalf("unexpected output:\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestCleaner_CleanFile_NoTruncateLeavesTrailingBytes(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.csv")
+	out := filepath.Join(dir, "out.csv")
+
+	if err := os.WriteFile(in, []byte("a,b\n"), 0644); err != nil {
+		t.Fatalf("seeding input file: %v", err)
+	}
+	if err := os.WriteFile(out, []byte("stale content longer than the new output\n"), 0644); err != nil {
+		t.Fatalf("seeding output file: %v", err)
+	}
+
+	c := New(WithComma(','))
+
+	if _, err := c.CleanFile(context.Background(), in, out, false, 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	const newContent = "a,b\n"
+	if string(got[:len(newContent)]) != newContent {
+		t.Fatalf("output does not start with new content: %q", got)
+	}
+	if len(got) <= len(newContent) {
+		t.Fatalf("expected stale trailing bytes to remain without truncate, got %q", got)
+	}
+}
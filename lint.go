@@ -0,0 +1,187 @@
+package csvclean
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// IssueKind categorizes a single problem found by Lint.
+type IssueKind string
+
+const (
+	// IssueFieldCount marks a record with a different field count than
+	// the header (or Cleaner.FieldsPerRecord).
+	IssueFieldCount IssueKind = "field_count"
+	// IssueBareQuote marks a quote character appearing outside of a
+	// quoted field.
+	IssueBareQuote IssueKind = "bare_quote"
+	// IssueQuote marks a malformed quoted field, e.g. a missing closing
+	// quote.
+	IssueQuote IssueKind = "quote"
+	// IssueInvalidUTF8 marks a line containing a byte sequence that is
+	// not valid UTF-8.
+	IssueInvalidUTF8 IssueKind = "invalid_utf8"
+	// IssueEmbeddedNUL marks a line containing a NUL byte.
+	IssueEmbeddedNUL IssueKind = "embedded_nul"
+	// IssueMixedLineEndings marks an input that uses both CRLF and LF
+	// line endings.
+	IssueMixedLineEndings IssueKind = "mixed_line_endings"
+	// IssueBOM marks an input that begins with a UTF-8 byte order mark.
+	IssueBOM IssueKind = "bom"
+)
+
+// Issue describes a single problem found by Lint. Line and Column are
+// 1-indexed. Line is 0 for an issue about the input as a whole
+// (IssueMixedLineEndings), and Column is 0 for IssueBOM and
+// IssueMixedLineEndings; IssueFieldCount carries Column 1, as reported by
+// encoding/csv.
+type Issue struct {
+	Kind    IssueKind `json:"kind"`
+	Line    int       `json:"line"`
+	Column  int       `json:"column"`
+	Message string    `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", i.Line, i.Column, i.Message)
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// Report is the result of a Lint pass.
+type Report struct {
+	Issues []Issue `json:"issues"`
+}
+
+// OK reports whether the linted input had no issues.
+func (r Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Lint reads delimited records from r and reports structural and encoding
+// problems without writing anything back out: wrong field counts, bare or
+// malformed quotes, invalid UTF-8, embedded NUL bytes, mixed CRLF/LF line
+// endings, and a leading byte order mark. It shares c's Comma, Comment,
+// FieldsPerRecord, and TrimLeadingSpace configuration with Clean, but
+// always parses with LazyQuotes disabled so quoting problems surface as
+// issues instead of being silently accepted.
+func (c *Cleaner) Lint(ctx context.Context, r io.Reader) (Report, error) {
+	var raw bytes.Buffer
+
+	reader := csv.NewReader(io.TeeReader(r, &raw))
+	reader.Comma = c.Comma
+	reader.Comment = c.Comment
+	reader.LazyQuotes = false
+	reader.FieldsPerRecord = c.FieldsPerRecord
+	reader.TrimLeadingSpace = c.TrimLeadingSpace
+
+	var report Report
+
+	for {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+
+		_, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				report.Issues = append(report.Issues, issueFromParseError(parseErr))
+				continue
+			}
+			return report, fmt.Errorf("error reading record: %w", err)
+		}
+	}
+
+	report.Issues = append(report.Issues, scanRawIssues(raw.Bytes())...)
+
+	return report, nil
+}
+
+func issueFromParseError(e *csv.ParseError) Issue {
+	kind := IssueQuote
+	switch {
+	case errors.Is(e.Err, csv.ErrFieldCount):
+		kind = IssueFieldCount
+	case errors.Is(e.Err, csv.ErrBareQuote):
+		kind = IssueBareQuote
+	}
+	return Issue{Kind: kind, Line: e.Line, Column: e.Column, Message: e.Err.Error()}
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// scanRawIssues inspects the raw bytes Lint's csv.Reader consumed for
+// problems encoding/csv itself doesn't surface: a leading BOM, invalid
+// UTF-8, embedded NULs, and inconsistent line endings.
+func scanRawIssues(data []byte) []Issue {
+	var issues []Issue
+
+	if bytes.HasPrefix(data, utf8BOM) {
+		issues = append(issues, Issue{Kind: IssueBOM, Line: 1, Message: "input begins with a UTF-8 byte order mark"})
+	}
+
+	var sawCRLF, sawLF bool
+	lineNum := 0
+	rest := data
+	for len(rest) > 0 {
+		lineNum++
+
+		line := rest
+		hasNewline := false
+		if idx := bytes.IndexByte(rest, '\n'); idx >= 0 {
+			line = rest[:idx]
+			rest = rest[idx+1:]
+			hasNewline = true
+		} else {
+			rest = nil
+		}
+
+		isCRLF := hasNewline && len(line) > 0 && line[len(line)-1] == '\r'
+		if isCRLF {
+			line = line[:len(line)-1]
+		}
+		if hasNewline {
+			if isCRLF {
+				sawCRLF = true
+			} else {
+				sawLF = true
+			}
+		}
+
+		if col := firstInvalidUTF8(line); col >= 0 {
+			issues = append(issues, Issue{Kind: IssueInvalidUTF8, Line: lineNum, Column: col + 1, Message: "invalid UTF-8 encoding"})
+		}
+		if idx := bytes.IndexByte(line, 0); idx >= 0 {
+			issues = append(issues, Issue{Kind: IssueEmbeddedNUL, Line: lineNum, Column: idx + 1, Message: "embedded NUL byte"})
+		}
+	}
+
+	if sawCRLF && sawLF {
+		issues = append(issues, Issue{Kind: IssueMixedLineEndings, Line: 0, Message: "input mixes CRLF and LF line endings"})
+	}
+
+	return issues
+}
+
+// firstInvalidUTF8 returns the byte offset of the first invalid UTF-8
+// sequence in line, or -1 if line is valid UTF-8.
+func firstInvalidUTF8(line []byte) int {
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRune(line[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
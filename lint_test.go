@@ -0,0 +1,75 @@
+package csvclean
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestCleaner_Lint_OK(t *testing.T) {
+	c := New(WithComma(','), WithHeader(true))
+
+	in := bytes.NewBufferString("name,age\nalice,30\nbob,40\n")
+
+	report, err := c.Lint(context.Background(), in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.OK() {
+		t.Fatalf("expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestCleaner_Lint_FieldCountAndBareQuote(t *testing.T) {
+	c := New(WithComma(','), WithHeader(true))
+
+	in := bytes.NewBufferString("name,age\nalice\nbo\"b,40\n")
+
+	report, err := c.Lint(context.Background(), in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var sawFieldCount, sawBareQuote bool
+	for _, issue := range report.Issues {
+		switch issue.Kind {
+		case IssueFieldCount:
+			sawFieldCount = true
+		case IssueBareQuote:
+			sawBareQuote = true
+		}
+	}
+	if !sawFieldCount {
+		t.Errorf("expected a field_count issue, got %+v", report.Issues)
+	}
+	if !sawBareQuote {
+		t.Errorf("expected a bare_quote issue, got %+v", report.Issues)
+	}
+}
+
+func TestCleaner_Lint_MixedLineEndingsAndBOM(t *testing.T) {
+	c := New(WithComma(','))
+
+	in := bytes.NewBuffer(append(utf8BOM, []byte("a,b\r\nc,d\n")...))
+
+	report, err := c.Lint(context.Background(), in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var sawBOM, sawMixed bool
+	for _, issue := range report.Issues {
+		switch issue.Kind {
+		case IssueBOM:
+			sawBOM = true
+		case IssueMixedLineEndings:
+			sawMixed = true
+		}
+	}
+	if !sawBOM {
+		t.Errorf("expected a bom issue, got %+v", report.Issues)
+	}
+	if !sawMixed {
+		t.Errorf("expected a mixed_line_endings issue, got %+v", report.Issues)
+	}
+}
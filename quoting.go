@@ -0,0 +1,61 @@
+package csvclean
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QuoteMinimal quotes a field when it contains the delimiter, the
+	// quote character, a carriage return, or a line feed. With '"' as the
+	// quote character the decision is left to encoding/csv.Writer, which
+	// also quotes a field that begins with a space or tab and the field
+	// `\.`; with any other quote character no other field is quoted.
+	QuoteMinimal QuotingPolicy = iota
+	// QuoteAll quotes every field unconditionally. This is csvclean's
+	// original behavior, now with embedded quote characters properly
+	// doubled per RFC 4180 instead of left unescaped.
+	QuoteAll
+	// QuoteNonNumeric quotes every field that does not parse as a Go
+	// float64, leaving numeric columns bare.
+	QuoteNonNumeric
+	// QuoteNone never quotes a field. A field containing the delimiter,
+	// the quote character, a carriage return, or a line feed causes
+	// Clean to return an error rather than emit ambiguous output.
+	QuoteNone
+)
+
+// fieldNeedsQuoting reports whether value contains a byte that would make
+// it ambiguous to parse back out unquoted.
+func fieldNeedsQuoting(value string, comma, quote rune) bool {
+	return strings.ContainsRune(value, comma) ||
+		strings.ContainsRune(value, quote) ||
+		strings.ContainsAny(value, "\r\n")
+}
+
+// shouldQuote applies c's QuotingPolicy to value, returning whether it must
+// be quoted on output.
+func (c *Cleaner) shouldQuote(value string) (bool, error) {
+	switch c.QuotingPolicy {
+	case QuoteAll:
+		return true, nil
+	case QuoteNonNumeric:
+		_, err := strconv.ParseFloat(value, 64)
+		return err != nil, nil
+	case QuoteNone:
+		if fieldNeedsQuoting(value, c.Comma, c.Quote) {
+			return false, fmt.Errorf("field %q requires quoting under QuoteNone", value)
+		}
+		return false, nil
+	default: // QuoteMinimal
+		return fieldNeedsQuoting(value, c.Comma, c.Quote), nil
+	}
+}
+
+// quoteField wraps value in c.Quote, doubling any embedded occurrences of
+// the quote character per RFC 4180.
+func (c *Cleaner) quoteField(value string) string {
+	escaped := strings.ReplaceAll(value, string(c.Quote), string(c.Quote)+string(c.Quote))
+	return string(c.Quote) + escaped + string(c.Quote)
+}
@@ -0,0 +1,50 @@
+package csvclean
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestCleaner_Clean_QuoteMinimal(t *testing.T) {
+	c := New(WithComma(','), WithQuote('"'))
+
+	in := bytes.NewBufferString("alice,30\n\"bob, the builder\",40\n")
+	var out bytes.Buffer
+
+	if _, err := c.Clean(context.Background(), in, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "alice,30\n\"bob, the builder\",40\n"
+	if got := out.String(); got != want {
+		t.Fatalf("unexpected output:\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestCleaner_Clean_QuoteNonNumeric(t *testing.T) {
+	c := New(WithComma(','), WithQuote('"'), WithQuotingPolicy(QuoteNonNumeric))
+
+	in := bytes.NewBufferString("alice,30,30.5\n")
+	var out bytes.Buffer
+
+	if _, err := c.Clean(context.Background(), in, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "\"alice\",30,30.5\n"
+	if got := out.String(); got != want {
+		t.Fatalf("unexpected output:\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestCleaner_Clean_QuoteNone_Error(t *testing.T) {
+	c := New(WithComma(','), WithQuote('"'), WithQuotingPolicy(QuoteNone))
+
+	in := bytes.NewBufferString("alice,\"has,comma\"\n")
+	var out bytes.Buffer
+
+	if _, err := c.Clean(context.Background(), in, &out); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}